@@ -0,0 +1,127 @@
+/*
+ * Copyright(c) 2019 Geoffroy Vallee All rights reserved.
+ * This software is licensed under a 3-clause BSD license. Please consult the
+ * LICENSE.md file distributed with the sources of this project regarding your
+ * rights to use or distribute this software.
+ */
+
+package pool
+
+import (
+	"sync"
+	"testing"
+
+	pubpool "github.com/gvallee/memory_pool/pkg/pool"
+)
+
+// legacyBytePool replicates the byte-at-a-time, chan-byte implementation
+// this package used to have, kept here only so the benchmarks below can
+// show how much the sync.Pool-backed Pool improves on it.
+type legacyBytePool struct {
+	objSize int64
+	lock    sync.Mutex
+	data    chan byte
+}
+
+func (p *legacyBytePool) init(nObj int64) {
+	p.data = make(chan byte, nObj*p.objSize)
+	for i := 0; i < cap(p.data); i++ {
+		var b byte
+		p.data <- b
+	}
+}
+
+func (p *legacyBytePool) get() []byte {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	o := make([]byte, 0, p.objSize)
+	var i int64
+	for i = 0; i < p.objSize; i++ {
+		o = append(o, <-p.data)
+	}
+	return o
+}
+
+func (p *legacyBytePool) put(o []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, b := range o {
+		p.data <- b
+	}
+}
+
+func touch(b []byte) {
+	for i := range b {
+		b[i] = byte('A')
+	}
+}
+
+func benchmarkLegacyBytePool(b *testing.B, n int) {
+	p := &legacyBytePool{objSize: int64(n)}
+	p.init(1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		o := p.get()
+		touch(o)
+		p.put(o)
+	}
+}
+
+func benchmarkChanSlicePool(b *testing.B, n int) {
+	p := &pubpool.Pool{
+		ObjSize:    int64(n),
+		NObj:       1,
+		GrowFactor: 0,
+	}
+	p.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		o := p.Get()
+		touch(o)
+		p.Return(o)
+	}
+}
+
+func benchmarkSyncPool(b *testing.B, n int) {
+	p := &Pool{
+		ObjSize:    int64(n),
+		NObj:       1,
+		GrowFactor: 1,
+	}
+	p.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		o := p.Get()
+		touch(o)
+		p.Return(o)
+	}
+}
+
+func BenchmarkSmallSizeLegacyBytePool(b *testing.B) {
+	benchmarkLegacyBytePool(b, 8)
+}
+
+func BenchmarkSmallSizeChanSlicePool(b *testing.B) {
+	benchmarkChanSlicePool(b, 8)
+}
+
+func BenchmarkSmallSizeSyncPool(b *testing.B) {
+	benchmarkSyncPool(b, 8)
+}
+
+func BenchmarkBigSizesLegacyBytePool(b *testing.B) {
+	benchmarkLegacyBytePool(b, 1024*1024)
+}
+
+func BenchmarkBigSizesChanSlicePool(b *testing.B) {
+	benchmarkChanSlicePool(b, 1024*1024)
+}
+
+func BenchmarkBigSizesSyncPool(b *testing.B) {
+	benchmarkSyncPool(b, 1024*1024)
+}
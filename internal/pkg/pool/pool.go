@@ -11,47 +11,34 @@ import (
 	"sync"
 )
 
-// Pool is the data structure representing a memory pool
+// Pool is the data structure representing a memory pool. It is backed by a
+// sync.Pool so that allocation and reuse of the underlying buffers is
+// delegated to the Go runtime instead of being implemented by hand.
 type Pool struct {
 	ObjSize    int64
 	NObj       int64
 	GrowFactor int8
 	Erase      bool
-	lock       sync.Mutex
-	data       chan byte
+	inner      sync.Pool
 }
 
 // Obj represents an object from a memory pool
 type Obj []byte
 
-func (p *Pool) growPool(newSize int64) error {
-	if p == nil {
-		return nil
-	}
-
-	sizeToAdd := newSize - (p.ObjSize * p.NObj)
-	// Increase the capacity
-	p.data = make(chan byte, newSize)
-	var i int64
-	// The channel has enough space, we create allocate the new (and only the new) memory
-	for i = 0; i < sizeToAdd; i++ {
-		var b byte
-		p.data <- b
-	}
-
-	return nil
-}
-
 // New initializes a new memory pool
 func (p *Pool) New() error {
 	if p == nil {
 		return nil
 	}
 
-	p.data = make(chan byte, p.NObj*p.ObjSize)
-	for i := 0; i < cap(p.data); i++ {
-		var b byte
-		p.data <- b
+	// p.inner deliberately has no New function: an empty sync.Pool.Get()
+	// then returns nil, which lets Get() below decide whether growing
+	// (i.e. allocating a new backing slice) is allowed at all.
+	p.inner = sync.Pool{}
+
+	var i int64
+	for i = 0; i < p.NObj; i++ {
+		p.inner.Put(make([]byte, p.ObjSize))
 	}
 
 	return nil
@@ -63,30 +50,18 @@ func (p *Pool) Get() Obj {
 		return nil
 	}
 
-	// Check if we have an object available
-	if len(p.data) == 0 {
-		// If not, can we grow the pool? If not return an error
-		if p.GrowFactor <= 0 {
-			return nil
-		}
-
-		// Grow the pool
-		totalSize := (p.NObj * p.ObjSize) * int64(p.GrowFactor)
-		p.growPool(totalSize)
+	if o := p.inner.Get(); o != nil {
+		return o.([]byte)
 	}
 
-	// Lock the pool
-	p.lock.Lock()
-	defer p.lock.Unlock()
-
-	// Get an object from the passive queue
-	var i int64
-	var o []byte // empty slice we add bytes from the pool
-	for i = 0; i < p.ObjSize; i++ {
-		o = append(o, <-p.data)
+	// The pool is empty: only allocate a new backing slice if growth is
+	// allowed, otherwise report exhaustion the same way the channel-based
+	// implementation used to.
+	if p.GrowFactor <= 0 {
+		return nil
 	}
 
-	return o
+	return make([]byte, p.ObjSize)
 }
 
 // Return puts an object into the memory pool for later reuse
@@ -102,13 +77,7 @@ func (p *Pool) Return(o Obj) error {
 		}
 	}
 
-	// Lock the active queue
-	p.lock.Lock()
-	defer p.lock.Unlock()
-
-	for i = 0; i < p.ObjSize; i++ {
-		p.data <- o[i]
-	}
+	p.inner.Put([]byte(o))
 
 	return nil
 }
@@ -0,0 +1,123 @@
+/*
+ * Copyright(c) 2019 Geoffroy Vallee. All rights reserved.
+ * This software is licensed under a 3-clause BSD license. Please consult the
+ * LICENSE.md file distributed with the sources of this project regarding your
+ * rights to use or distribute this software.
+ */
+
+package pool
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// numBuckets is the number of power-of-two sub-pools a BufferPool keeps,
+// covering buffer sizes from 2^0 up to 2^32 bytes.
+const numBuckets = 33
+
+// DefaultNObj is the number of objects a BufferPool's sub-pools start with
+// when it is not explicitly configured.
+const DefaultNObj = 8
+
+// DefaultGrowFactor is the growth factor a BufferPool's sub-pools use when
+// it is not explicitly configured.
+const DefaultGrowFactor = 2
+
+// BufferPool is a collection of size-bucketed memory pools, one per power of
+// two from 2^0 to 2^32 bytes. It lets callers request arbitrarily sized
+// buffers without having to pre-declare a fixed ObjSize, which the plain
+// Pool type requires.
+type BufferPool struct {
+	// NObj is the initial number of objects each sub-pool is created with.
+	NObj int64
+	// GrowFactor is the growth factor applied to each sub-pool.
+	GrowFactor int8
+
+	mu    sync.Mutex
+	pools [numBuckets]*Pool
+}
+
+// GlobalPool is a ready-to-use BufferPool for callers that do not need a
+// dedicated configuration.
+var GlobalPool = &BufferPool{
+	NObj:       DefaultNObj,
+	GrowFactor: DefaultGrowFactor,
+}
+
+// bucket returns the sub-pool for a given power-of-two index, lazily
+// creating the underlying Pool on first use.
+func (bp *BufferPool) bucket(idx int) *Pool {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	if bp.pools[idx] == nil {
+		nObj := bp.NObj
+		if nObj <= 0 {
+			nObj = DefaultNObj
+		}
+		growFactor := bp.GrowFactor
+		if growFactor <= 0 {
+			growFactor = DefaultGrowFactor
+		}
+
+		p := &Pool{
+			ObjSize:    int64(1) << uint(idx),
+			NObj:       nObj,
+			GrowFactor: growFactor,
+		}
+		p.New()
+		bp.pools[idx] = p
+	}
+
+	return bp.pools[idx]
+}
+
+// Get returns a buffer of length n. Internally, n is rounded up to the next
+// power of two to pick the matching sub-pool, and the buffer handed back to
+// the caller is resliced down to exactly n bytes.
+func (bp *BufferPool) Get(n int64) []byte {
+	if bp == nil || n <= 0 {
+		return nil
+	}
+
+	idx := bits.Len64(uint64(n - 1))
+	if idx >= numBuckets {
+		return nil
+	}
+
+	b := bp.bucket(idx).Get()
+	if b == nil {
+		return nil
+	}
+
+	return b[:n]
+}
+
+// Put returns a buffer obtained from Get (or of compatible capacity) to the
+// pool. The buffer is routed to the sub-pool matching cap(b) rounded down to
+// a power of two; buffers smaller than the smallest bucket or larger than
+// the largest one are silently dropped. A buffer whose sub-pool has not yet
+// been created by a Get is dropped as well.
+func (bp *BufferPool) Put(b []byte) {
+	if bp == nil || len(b) == 0 {
+		return
+	}
+
+	idx := bits.Len64(uint64(cap(b))) - 1
+	if idx < 0 || idx >= numBuckets {
+		return
+	}
+
+	bp.mu.Lock()
+	p := bp.pools[idx]
+	bp.mu.Unlock()
+	if p == nil {
+		return
+	}
+
+	// cap(b) may not be an exact power of two (e.g. after appends); rounding
+	// down picks a bucket no larger than cap(b), so b is resliced to that
+	// bucket's size.
+	p.Return(b[:1<<uint(idx)])
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright(c) 2019 Geoffroy Vallee. All rights reserved.
+ * This software is licensed under a 3-clause BSD license. Please consult the
+ * LICENSE.md file distributed with the sources of this project regarding your
+ * rights to use or distribute this software.
+ */
+
+package pool
+
+import (
+	"testing"
+)
+
+func TestBufferPoolGetExactSize(t *testing.T) {
+	bp := &BufferPool{
+		NObj:       2,
+		GrowFactor: 2,
+	}
+
+	b := bp.Get(10)
+	if b == nil {
+		t.Fatal("failed to get a buffer")
+	}
+	if len(b) != 10 {
+		t.Fatalf("buffer is of the wrong size (%d vs. 10)", len(b))
+	}
+	if cap(b) != 16 {
+		t.Fatalf("buffer capacity is of the wrong bucket (%d vs. 16)", cap(b))
+	}
+}
+
+func TestBufferPoolPutGet(t *testing.T) {
+	bp := &BufferPool{
+		NObj:       1,
+		GrowFactor: 2,
+	}
+
+	b := bp.Get(100)
+	if b == nil {
+		t.Fatal("failed to get a buffer")
+	}
+	bp.Put(b)
+
+	b2 := bp.Get(100)
+	if b2 == nil {
+		t.Fatal("failed to get a buffer after returning one")
+	}
+	if len(b2) != 100 {
+		t.Fatalf("buffer is of the wrong size (%d vs. 100)", len(b2))
+	}
+}
+
+func TestBufferPoolPutTooSmall(t *testing.T) {
+	bp := &BufferPool{
+		NObj:       1,
+		GrowFactor: 2,
+	}
+
+	// A buffer smaller than the smallest bucket should be silently dropped,
+	// not panic.
+	bp.Put(nil)
+}
+
+func TestGlobalPool(t *testing.T) {
+	b := GlobalPool.Get(64)
+	if b == nil {
+		t.Fatal("failed to get a buffer from the global pool")
+	}
+	if len(b) != 64 {
+		t.Fatalf("buffer is of the wrong size (%d vs. 64)", len(b))
+	}
+	GlobalPool.Put(b)
+}
@@ -7,6 +7,12 @@
 
 package pool
 
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
 // Obj represents an object from a memory pool
 //type Obj []byte
 
@@ -16,7 +22,52 @@ type Pool struct {
 	NObj       int64
 	GrowFactor int8
 	Erase      bool
-	data       chan []byte // channels have a built-in locking mechanism, no need to protect it with a mutex
+	// MaxCap, when set to a value greater than zero, caps how many objects
+	// the pool is allowed to grow to regardless of GrowFactor. Get returns
+	// nil once the pool is exhausted and MaxCap has been reached.
+	MaxCap int64
+	// OnGrow, when set, is invoked every time the pool grows, with the
+	// object capacity before and after the growth.
+	OnGrow func(oldCap, newCap int64)
+
+	// dataMu guards all access to data: growPool replaces the channel
+	// outright when growing, so even reading p.data to receive from it
+	// must be synchronized against that replacement, not just the growth
+	// decision itself. Readers (Get, TryGet, GetContext, Return) take the
+	// read lock; tryGrowAndGet takes the write lock around growPool's
+	// channel swap.
+	dataMu sync.RWMutex
+	data   chan []byte
+
+	gets   int64
+	puts   int64
+	grows  int64
+	misses int64
+	allocs int64
+}
+
+// PoolStats is a snapshot of a Pool's usage counters, returned by Stats.
+type PoolStats struct {
+	Gets   int64
+	Puts   int64
+	Grows  int64
+	Misses int64
+	Allocs int64
+}
+
+// Stats returns a snapshot of the pool's usage counters.
+func (p *Pool) Stats() PoolStats {
+	if p == nil {
+		return PoolStats{}
+	}
+
+	return PoolStats{
+		Gets:   atomic.LoadInt64(&p.gets),
+		Puts:   atomic.LoadInt64(&p.puts),
+		Grows:  atomic.LoadInt64(&p.grows),
+		Misses: atomic.LoadInt64(&p.misses),
+		Allocs: atomic.LoadInt64(&p.allocs),
+	}
 }
 
 func (p *Pool) growPool(nNewObj int64) error {
@@ -24,14 +75,29 @@ func (p *Pool) growPool(nNewObj int64) error {
 		return nil
 	}
 
+	oldCap := p.NObj
+	if p.MaxCap > 0 && nNewObj > p.MaxCap {
+		nNewObj = p.MaxCap
+	}
+	if nNewObj <= oldCap {
+		return nil
+	}
+
 	// Increase the size of the channel
 	p.data = make(chan []byte, nNewObj)
 	// The channel has enough space, we create allocate the new (and only the new) memory
 	var i int64
-	for i = 0; i < nNewObj-p.NObj; i++ {
+	for i = 0; i < nNewObj-oldCap; i++ {
 		b := make([]byte, p.ObjSize)
 		p.data <- b
 	}
+	atomic.AddInt64(&p.allocs, nNewObj-oldCap)
+	atomic.AddInt64(&p.grows, 1)
+	p.NObj = nNewObj
+
+	if p.OnGrow != nil {
+		p.OnGrow(oldCap, nNewObj)
+	}
 
 	return nil
 }
@@ -48,29 +114,185 @@ func (p *Pool) New() error {
 		b := make([]byte, p.ObjSize)
 		p.data <- b
 	}
+	atomic.AddInt64(&p.allocs, p.NObj)
 
 	return nil
 }
 
-// Get returns an object from a memory pool
+// Get returns an object from a memory pool. It never blocks: if the pool is
+// empty and cannot grow, it returns nil immediately.
 func (p *Pool) Get() []byte {
 	if p == nil {
 		return nil
 	}
 
-	// Check if we have an object available
-	if len(p.data) == 0 {
-		// If not, can we grow the pool? If not return an error
-		if p.GrowFactor <= 0 {
+	p.dataMu.RLock()
+	select {
+	case o := <-p.data:
+		p.dataMu.RUnlock()
+		atomic.AddInt64(&p.gets, 1)
+		return o
+	default:
+		p.dataMu.RUnlock()
+	}
+
+	// The pool looked empty. Can we grow it? If not, report a miss.
+	if p.GrowFactor <= 0 {
+		atomic.AddInt64(&p.misses, 1)
+		return nil
+	}
+
+	// tryGrowAndGet takes the write lock, which both serializes growth
+	// against other growers and excludes readers for the duration of the
+	// channel swap, so a Get can never observe a half-replaced p.data.
+	o, grew := p.tryGrowAndGet()
+	if grew {
+		return o
+	}
+
+	atomic.AddInt64(&p.misses, 1)
+	return nil
+}
+
+// tryGrowAndGet grows the pool, if needed, and takes an object from it,
+// holding dataMu for write for the whole operation. The second return value
+// is false when the pool is still empty after growing.
+func (p *Pool) tryGrowAndGet() ([]byte, bool) {
+	p.dataMu.Lock()
+	defer p.dataMu.Unlock()
+
+	// Another goroutine may have grown the pool, or returned an object to
+	// it, while we were waiting for the lock.
+	select {
+	case o := <-p.data:
+		atomic.AddInt64(&p.gets, 1)
+		return o, true
+	default:
+	}
+
+	p.growPool(p.NObj * int64(p.GrowFactor))
+
+	select {
+	case o := <-p.data:
+		atomic.AddInt64(&p.gets, 1)
+		return o, true
+	default:
+		return nil, false
+	}
+}
+
+// TryGet is a non-blocking variant of Get: it takes an object from the pool
+// if one is immediately available, without attempting to grow the pool, and
+// returns nil otherwise.
+func (p *Pool) TryGet() []byte {
+	if p == nil {
+		return nil
+	}
+
+	p.dataMu.RLock()
+	defer p.dataMu.RUnlock()
+
+	select {
+	case o := <-p.data:
+		atomic.AddInt64(&p.gets, 1)
+		return o
+	default:
+		atomic.AddInt64(&p.misses, 1)
+		return nil
+	}
+}
+
+// GetContext is like Get but also respects ctx: if ctx is canceled before an
+// object becomes available, GetContext returns ctx.Err() instead of blocking
+// or returning a plain miss.
+func (p *Pool) GetContext(ctx context.Context) ([]byte, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	p.dataMu.RLock()
+	select {
+	case o := <-p.data:
+		p.dataMu.RUnlock()
+		atomic.AddInt64(&p.gets, 1)
+		return o, nil
+	case <-ctx.Done():
+		p.dataMu.RUnlock()
+		return nil, ctx.Err()
+	default:
+		p.dataMu.RUnlock()
+	}
+
+	if p.GrowFactor <= 0 {
+		atomic.AddInt64(&p.misses, 1)
+		return nil, nil
+	}
+
+	o, grew := p.tryGrowAndGet()
+	if grew {
+		return o, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		atomic.AddInt64(&p.misses, 1)
+		return nil, nil
+	}
+}
+
+// GetN returns n objects from a memory pool at once. This is useful for
+// callers that need several same-sized buffers together, e.g. one shard per
+// disk when reconstructing an erasure-coded stripe. If the pool runs out of
+// objects partway through, the objects already acquired are returned to the
+// pool and GetN returns nil rather than handing back a partial batch.
+func (p *Pool) GetN(n int) [][]byte {
+	if p == nil || n <= 0 {
+		return nil
+	}
+
+	bs := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		b := p.Get()
+		if b == nil {
+			for _, acquired := range bs {
+				p.Return(acquired)
+			}
 			return nil
 		}
+		bs = append(bs, b)
+	}
+
+	return bs
+}
+
+// ReturnN puts a batch of objects obtained from GetN back into the memory
+// pool.
+func (p *Pool) ReturnN(bs [][]byte) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, b := range bs {
+		if err := p.Return(b); err != nil {
+			return err
+		}
+	}
 
-		// Grow the pool
-		p.growPool(p.NObj * int64(p.GrowFactor))
+	return nil
+}
+
+// Reslice shrinks b to length n without losing its underlying capacity, so
+// that it can later be returned to the pool it came from. This mirrors the
+// reslice-on-last-iteration pattern used when a final block is shorter than
+// the others, e.g. the last chunk of an erasure-coded stripe.
+func Reslice(b []byte, n int64) []byte {
+	if b == nil {
+		return nil
 	}
 
-	// Get an object from the passive queue
-	return <-p.data
+	return b[:n]
 }
 
 // Return puts an object into the memory pool for later reuse
@@ -86,7 +308,21 @@ func (p *Pool) Return(o []byte) error {
 		}
 	}
 
-	p.data <- o
+	// growPool can replace p.data with a new channel; hold the read lock
+	// while sending so a Return in flight during a grow cannot write into
+	// a channel that is about to be discarded. The send itself is
+	// non-blocking: the channel is sized to the pool's capacity, so a
+	// full channel means more objects are being returned than were ever
+	// taken out (e.g. a double Return), and blocking here while holding
+	// the lock would otherwise deadlock the whole pool. o is stored at its
+	// full capacity so that a buffer shortened with Reslice goes back into
+	// the pool at its original length.
+	p.dataMu.RLock()
+	select {
+	case p.data <- o[:cap(o)]:
+	default:
+	}
+	p.dataMu.RUnlock()
 	/*
 		for i = 0; i < p.ObjSize; i++ {
 			p.data <- (*o)[i]
@@ -94,6 +330,7 @@ func (p *Pool) Return(o []byte) error {
 	*/
 
 	o = nil
+	atomic.AddInt64(&p.puts, 1)
 
 	return nil
 }
@@ -8,6 +8,7 @@
 package pool
 
 import (
+	"context"
 	"encoding/binary"
 	"testing"
 )
@@ -186,3 +187,200 @@ func TestGrow(t *testing.T) {
 		t.Fatalf("pool size of incorrect of growth (%d vs. %d)", len(p.data), 3*p.ObjSize)
 	}
 }
+
+func TestStats(t *testing.T) {
+	p := Pool{
+		ObjSize:    8,
+		NObj:       1,
+		Erase:      false,
+		GrowFactor: 2,
+	}
+
+	p.New()
+
+	obj1 := p.Get()
+	if obj1 == nil {
+		t.Fatal("failed to get object")
+	}
+	obj2 := p.Get() // should trigger a grow
+	if obj2 == nil {
+		t.Fatal("failed to get object")
+	}
+
+	p.Return(obj1)
+	p.Return(obj2)
+
+	stats := p.Stats()
+	if stats.Gets != 2 {
+		t.Fatalf("wrong number of gets (%d vs. 2)", stats.Gets)
+	}
+	if stats.Puts != 2 {
+		t.Fatalf("wrong number of puts (%d vs. 2)", stats.Puts)
+	}
+	if stats.Grows != 1 {
+		t.Fatalf("wrong number of grows (%d vs. 1)", stats.Grows)
+	}
+	if stats.Allocs != 2 {
+		t.Fatalf("wrong number of allocs (%d vs. 2)", stats.Allocs)
+	}
+}
+
+func TestMaxCap(t *testing.T) {
+	var gotOldCap, gotNewCap int64
+	p := Pool{
+		ObjSize:    8,
+		NObj:       1,
+		Erase:      false,
+		GrowFactor: 10,
+		MaxCap:     2,
+		OnGrow: func(oldCap, newCap int64) {
+			gotOldCap = oldCap
+			gotNewCap = newCap
+		},
+	}
+
+	p.New()
+
+	obj1 := p.Get()
+	if obj1 == nil {
+		t.Fatal("failed to get object")
+	}
+	obj2 := p.Get() // should grow, but clamped to MaxCap
+	if obj2 == nil {
+		t.Fatal("failed to get object")
+	}
+	if gotOldCap != 1 || gotNewCap != 2 {
+		t.Fatalf("OnGrow was not called with the expected capacities (%d -> %d vs. 1 -> 2)", gotOldCap, gotNewCap)
+	}
+
+	obj3 := p.Get() // pool is at MaxCap and empty, should miss
+	if obj3 != nil {
+		t.Fatal("pool grew past MaxCap")
+	}
+
+	stats := p.Stats()
+	if stats.Misses != 1 {
+		t.Fatalf("wrong number of misses (%d vs. 1)", stats.Misses)
+	}
+}
+
+func TestTryGet(t *testing.T) {
+	p := Pool{
+		ObjSize:    8,
+		NObj:       1,
+		Erase:      false,
+		GrowFactor: 0,
+	}
+
+	p.New()
+
+	obj1 := p.TryGet()
+	if obj1 == nil {
+		t.Fatal("failed to get object")
+	}
+
+	t.Log("Pool is empty and cannot grow, TryGet should return nil right away...")
+	obj2 := p.TryGet()
+	if obj2 != nil {
+		t.Fatal("TryGet returned an object from an empty, non-growable pool")
+	}
+}
+
+func TestGetContext(t *testing.T) {
+	p := Pool{
+		ObjSize:    8,
+		NObj:       1,
+		Erase:      false,
+		GrowFactor: 2,
+	}
+
+	p.New()
+
+	obj1, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if obj1 == nil {
+		t.Fatal("failed to get object")
+	}
+
+	t.Log("Getting a second object, which should trigger a grow...")
+	obj2, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if obj2 == nil {
+		t.Fatal("failed to get object")
+	}
+
+	t.Log("Getting an object with an already-canceled context should fail with ctx.Err()...")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj3, err := p.GetContext(ctx)
+	if obj3 != nil {
+		t.Fatal("GetContext returned an object with a canceled context")
+	}
+	if err != context.Canceled {
+		t.Fatalf("wrong error returned (%v vs. %v)", err, context.Canceled)
+	}
+}
+
+func TestGetNReturnN(t *testing.T) {
+	p := Pool{
+		ObjSize:    8,     // Size of a single object for the pool
+		NObj:       4,     // Number of objects in the pool
+		Erase:      false, // We do not need to erase the data in the object when returning it to the pool
+		GrowFactor: 0,     // The memory pool cannot grow
+	}
+
+	p.New()
+
+	t.Log("Getting 4 objects at once...")
+	bs := p.GetN(4)
+	if bs == nil {
+		t.Fatal("failed to get a batch of objects")
+	}
+	if len(bs) != 4 {
+		t.Fatalf("batch is of the wrong size (%d vs. 4)", len(bs))
+	}
+
+	t.Log("Getting one more object than available, which should fail and not leak any object...")
+	more := p.GetN(1)
+	if more != nil {
+		t.Fatal("we were able to get more objects than the capacity")
+	}
+
+	err := p.ReturnN(bs)
+	if err != nil {
+		t.Fatal("failed to return a batch of objects")
+	}
+
+	// Now that the batch was returned, the pool should be usable again
+	obj := p.Get()
+	if obj == nil {
+		t.Fatal("failed to get object after returning a batch")
+	}
+}
+
+func TestReslice(t *testing.T) {
+	p := Pool{
+		ObjSize:    16,
+		NObj:       1,
+		Erase:      false,
+		GrowFactor: 0,
+	}
+
+	p.New()
+	obj := p.Get()
+	if obj == nil {
+		t.Fatal("failed to get object")
+	}
+
+	s := Reslice(obj, 4)
+	if len(s) != 4 {
+		t.Fatalf("resliced object is of the wrong size (%d vs. 4)", len(s))
+	}
+	if cap(s) != cap(obj) {
+		t.Fatalf("reslicing should not change the underlying capacity (%d vs. %d)", cap(s), cap(obj))
+	}
+}